@@ -0,0 +1,45 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/truechain/truechain-engineering-code/eth/catalyst"
+	"github.com/truechain/truechain-engineering-code/node"
+)
+
+// CatalystFlag enables the engine_* JSON-RPC namespace (eth/catalyst), so an
+// external consensus client can drive fast-chain block production once the
+// snail chain has crossed its terminal total difficulty. It is off by
+// default: a node started without it keeps mining snail PoW blocks forever,
+// even past TTD, since nothing would otherwise take over sealing.
+var CatalystFlag = cli.BoolFlag{
+	Name:  "catalyst",
+	Usage: "Enable the engine API (engine_newPayloadV1, engine_forkchoiceUpdatedV1, engine_getPayloadV1) for an external consensus client",
+}
+
+// RegisterCatalystAPI registers the engine_* namespace on stack if --catalyst
+// was passed, so an external consensus client can reach it; it is a no-op
+// otherwise. Callers should invoke this during node setup, alongside the
+// rest of the namespace registration (eth, net, web3, ...).
+func RegisterCatalystAPI(ctx *cli.Context, stack *node.Node, backend catalyst.Backend) error {
+	if !ctx.GlobalBool(CatalystFlag.Name) {
+		return nil
+	}
+	return catalyst.Register(stack, backend)
+}
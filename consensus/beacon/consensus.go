@@ -0,0 +1,203 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package beacon implements the post-merge consensus engine for the TrueChain
+// fast chain. It wraps the pre-merge snail PoW engine and defers to it for
+// any header still below the terminal total difficulty, while blocks sealed
+// after the transition are driven entirely by an external consensus client
+// talking to eth/catalyst.
+package beacon
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/truechain/truechain-engineering-code/common"
+	"github.com/truechain/truechain-engineering-code/consensus"
+	"github.com/truechain/truechain-engineering-code/core/types"
+	"github.com/truechain/truechain-engineering-code/params"
+)
+
+// ErrInvalidTerminalBlock is returned when a post-merge header does not
+// satisfy the shape required of a beacon-sealed block.
+var ErrInvalidTerminalBlock = errors.New("invalid terminal block")
+
+// serenity is the PoS header difficulty shared by every block sealed after
+// the merge, matching the convention used by upstream go-ethereum.
+var serenityDifficulty = common.Big0
+
+// Beacon is a consensus engine combining the legacy snail PoW rules with the
+// PoS rules that take over once params.ChainConfig.TerminalTotalDifficulty is
+// reached. It's a half-functional consensus engine: all the methods that
+// retrieve current states are left to the inner engine (e.g. uncles before
+// TTD), while the methods to mint new blocks are overridden to produce
+// zero-difficulty headers whose randomness is supplied by the consensus
+// client via header.MixDigest (prevRandao).
+type Beacon struct {
+	// inner is the snail-PoW engine used for all pre-merge blocks. It is kept
+	// around after the transition only to answer historical queries.
+	inner consensus.Engine
+}
+
+// New creates a consensus engine that wraps inner (the legacy snail engine)
+// and becomes PoS-aware once the chain configuration sets a terminal total
+// difficulty.
+func New(inner consensus.Engine) *Beacon {
+	if _, ok := inner.(*Beacon); ok {
+		panic("can't nest beacon engines")
+	}
+	return &Beacon{inner: inner}
+}
+
+// IsPoSHeader reports whether a header belongs to the PoS-stage, i.e. it was
+// sealed with a zero difficulty.
+func (beacon *Beacon) IsPoSHeader(header *types.SnailHeader) bool {
+	if header.Difficulty == nil {
+		return false
+	}
+	return header.Difficulty.Cmp(serenityDifficulty) == 0
+}
+
+// Author implements consensus.Engine, returning the header's coinbase for
+// PoS blocks (there is no miner reward split to account for) and falling
+// back to the inner engine pre-merge.
+func (beacon *Beacon) Author(header *types.SnailHeader) (common.Address, error) {
+	if !beacon.IsPoSHeader(header) {
+		return beacon.inner.Author(header)
+	}
+	return header.Coinbase, nil
+}
+
+// CalcSnailDifficulty returns the zero difficulty mandated for every block
+// minted after the chain has crossed its terminal total difficulty, or
+// defers to the inner engine beforehand.
+func (beacon *Beacon) CalcSnailDifficulty(chain consensus.SnailChainReader, time uint64, parents []*types.SnailHeader) *big.Int {
+	if len(parents) == 0 {
+		return beacon.inner.CalcSnailDifficulty(chain, time, parents)
+	}
+	parent := parents[len(parents)-1]
+	if parent.Difficulty != nil && parent.Difficulty.Cmp(serenityDifficulty) == 0 {
+		return new(big.Int).Set(serenityDifficulty)
+	}
+	if parent.Number.Sign() == 0 {
+		// parent is the genesis block, which has no ancestor to sum a total
+		// difficulty from; genesis can't itself be past the TTD.
+		return beacon.inner.CalcSnailDifficulty(chain, time, parents)
+	}
+	td := chain.GetTd(parent.ParentHash, parent.Number.Uint64()-1)
+	if td != nil && chain.Config() != nil && chain.Config().TerminalTotalDifficulty != nil &&
+		new(big.Int).Add(td, parent.Difficulty).Cmp(chain.Config().TerminalTotalDifficulty) >= 0 {
+		return new(big.Int).Set(serenityDifficulty)
+	}
+	return beacon.inner.CalcSnailDifficulty(chain, time, parents)
+}
+
+// VerifyHeader checks whether a header conforms to the consensus rules of
+// either the legacy snail PoW engine or the simplified PoS rules, depending
+// on where the header falls relative to the TTD transition.
+func (beacon *Beacon) VerifyHeader(chain consensus.SnailChainReader, header *types.SnailHeader, seal bool) error {
+	if !beacon.IsPoSHeader(header) {
+		return beacon.inner.VerifyHeader(chain, header, seal)
+	}
+	return beacon.verifyPoSHeader(chain, header)
+}
+
+func (beacon *Beacon) verifyPoSHeader(chain consensus.SnailChainReader, header *types.SnailHeader) error {
+	parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	if header.Difficulty == nil || header.Difficulty.Cmp(serenityDifficulty) != 0 {
+		return ErrInvalidTerminalBlock
+	}
+	if len(header.Extra) > 32 {
+		return ErrInvalidTerminalBlock
+	}
+	return nil
+}
+
+// Prepare fills in missing header fields. Pre-merge this simply delegates to
+// the inner engine; post-merge the difficulty is fixed at zero and the only
+// remaining input is the prevRandao value already stashed in MixDigest by
+// eth/catalyst.
+func (beacon *Beacon) Prepare(chain consensus.SnailChainReader, header *types.SnailHeader) error {
+	if !beacon.IsPoSHeader(header) {
+		return beacon.inner.Prepare(chain, header)
+	}
+	header.Difficulty = new(big.Int).Set(serenityDifficulty)
+	return nil
+}
+
+// FinalizeSnail assembles the final block, crediting fruits and rewards
+// pre-merge, or simply applying withdrawals and returning the sealed block
+// unchanged once PoS sealing is in effect.
+func (beacon *Beacon) FinalizeSnail(chain consensus.SnailChainReader, header *types.SnailHeader, uncles []*types.SnailHeader, fruits []*types.SnailBlock, signs []*types.PbftSign) (*types.SnailBlock, error) {
+	if !beacon.IsPoSHeader(header) {
+		return beacon.inner.FinalizeSnail(chain, header, uncles, fruits, signs)
+	}
+	if len(uncles) != 0 {
+		return nil, errors.New("uncles not allowed after the merge")
+	}
+	return types.NewSnailBlock(header, fruits, signs, nil), nil
+}
+
+// Seal mints a new block. Pre-merge this is the PoW search; post-merge there
+// is nothing to search for, the header is already fully specified by the
+// consensus client and Seal just returns it.
+func (beacon *Beacon) Seal(chain consensus.SnailChainReader, block *types.SnailBlock, results chan<- *types.SnailBlock, stop <-chan struct{}) error {
+	if !beacon.IsPoSHeader(block.Header()) {
+		return beacon.inner.Seal(chain, block, results, stop)
+	}
+	results <- block
+	return nil
+}
+
+// SealHash returns the hash of a block prior to it being sealed.
+func (beacon *Beacon) SealHash(header *types.SnailHeader) common.Hash {
+	return beacon.inner.SealHash(header)
+}
+
+// Close shuts down the inner engine.
+func (beacon *Beacon) Close() error {
+	return beacon.inner.Close()
+}
+
+// InnerEngine returns the embedded snail PoW engine, mostly useful so that
+// eth/catalyst can reach through for historical, pre-merge verification.
+func (beacon *Beacon) InnerEngine() consensus.Engine {
+	return beacon.inner
+}
+
+// TransitionConfig is the subset of params.ChainConfig the consensus client
+// exchanges with the execution engine on startup to make sure both sides
+// agree on where the merge happens.
+type TransitionConfig struct {
+	TerminalTotalDifficulty *big.Int    `json:"terminalTotalDifficulty"`
+	TerminalBlockHash       common.Hash `json:"terminalBlockHash"`
+	TerminalBlockNumber     *big.Int    `json:"terminalBlockNumber"`
+}
+
+// ValidateTransitionConfig checks a remote TransitionConfig against the local
+// params.ChainConfig, as required by engine_exchangeTransitionConfigurationV1.
+func ValidateTransitionConfig(config *params.ChainConfig, remote *TransitionConfig) error {
+	if config.TerminalTotalDifficulty == nil || remote.TerminalTotalDifficulty == nil {
+		return errors.New("missing terminal total difficulty")
+	}
+	if config.TerminalTotalDifficulty.Cmp(remote.TerminalTotalDifficulty) != 0 {
+		return errors.New("terminal total difficulty mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package beacon
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/truechain/truechain-engineering-code/params"
+)
+
+func TestValidateTransitionConfigMismatch(t *testing.T) {
+	config := &params.ChainConfig{TerminalTotalDifficulty: big.NewInt(100)}
+	remote := &TransitionConfig{TerminalTotalDifficulty: big.NewInt(200)}
+	if err := ValidateTransitionConfig(config, remote); err == nil {
+		t.Error("expected an error for mismatched terminal total difficulty, got nil")
+	}
+}
+
+func TestValidateTransitionConfigMatch(t *testing.T) {
+	config := &params.ChainConfig{TerminalTotalDifficulty: big.NewInt(100)}
+	remote := &TransitionConfig{TerminalTotalDifficulty: big.NewInt(100)}
+	if err := ValidateTransitionConfig(config, remote); err != nil {
+		t.Errorf("expected matching terminal total difficulty to validate, got %v", err)
+	}
+}
+
+func TestValidateTransitionConfigMissing(t *testing.T) {
+	config := &params.ChainConfig{}
+	remote := &TransitionConfig{TerminalTotalDifficulty: big.NewInt(100)}
+	if err := ValidateTransitionConfig(config, remote); err == nil {
+		t.Error("expected an error when the local config has no terminal total difficulty, got nil")
+	}
+}
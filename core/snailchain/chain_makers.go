@@ -22,7 +22,6 @@ import (
 	"github.com/truechain/truechain-engineering-code/common"
 	"github.com/truechain/truechain-engineering-code/consensus"
 	"github.com/truechain/truechain-engineering-code/core/types"
-	"github.com/truechain/truechain-engineering-code/core/vm"
 	"github.com/truechain/truechain-engineering-code/ethdb"
 	"github.com/truechain/truechain-engineering-code/params"
 	"time"
@@ -41,8 +40,11 @@ type BlockGen struct {
 	//gasPool *GasPool
 	uncles  []*types.SnailHeader
 
-	fruits []*types.SnailBlock
-	signs  []*types.PbftSign
+	fruits        []*types.SnailBlock
+	fruitReceipts []*types.SnailBlockReceipt
+	signs         []*types.PbftSign
+
+	withdrawals []*types.Withdrawal
 
 	config *params.ChainConfig
 	engine consensus.Engine
@@ -63,6 +65,41 @@ func (b *BlockGen) AddFruit(block *types.SnailBlock) {
 	b.fruits = append(b.fruits, block)
 }
 
+// AddFruitWithReceipt is like AddFruit, but additionally records the fruit's
+// reward receipt so tests can assert on it without re-deriving it from the
+// fruit itself.
+func (b *BlockGen) AddFruitWithReceipt(block *types.SnailBlock, receipt *types.SnailBlockReceipt) {
+	b.fruits = append(b.fruits, block)
+	b.fruitReceipts = append(b.fruitReceipts, receipt)
+}
+
+// AddTxWithChain appends a PBFT signature to the generated block's fruit
+// signature set. It takes no chain parameter because, unlike a real
+// signature set, BlockGen doesn't validate the signer against any
+// SnailChainReader - callers that need that should verify tx before calling
+// AddTxWithChain.
+func (b *BlockGen) AddTxWithChain(tx *types.PbftSign) {
+	b.signs = append(b.signs, tx)
+}
+
+// Engine returns the consensus engine this BlockGen is minting blocks for.
+func (b *BlockGen) Engine() consensus.Engine {
+	return b.engine
+}
+
+// Config returns the chain configuration this BlockGen is minting blocks
+// under.
+func (b *BlockGen) Config() *params.ChainConfig {
+	return b.config
+}
+
+// AddWithdrawal appends a withdrawal to the generated block. The balance it
+// credits is applied directly at block-apply time by the finalizer, the same
+// way fruit rewards are, rather than going through the EVM.
+func (b *BlockGen) AddWithdrawal(w *types.Withdrawal) {
+	b.withdrawals = append(b.withdrawals, w)
+}
+
 // SetExtra sets the extra data field of the generated block.
 func (b *BlockGen) SetExtra(data []byte) {
 	b.header.Extra = data
@@ -120,37 +157,67 @@ func GenerateChain(config *params.ChainConfig, parent *types.SnailBlock, engine
 		config = params.TestChainConfig
 	}
 	blocks := make(types.SnailBlocks, n)
+	chainReader := newFakeChainReader(config, db, parent)
 	genblock := func(i int, parent *types.SnailBlock) *types.SnailBlock {
-		// TODO(karalabe): This is needed for clique, which depends on multiple blocks.
-		// It's nonetheless ugly to spin up a blockchain here. Get rid of this somehow.
-		blockchain, _ := NewSnailBlockChain(db, nil, config, engine, vm.Config{})
-		defer blockchain.Stop()
+		// Once the terminal total difficulty has been reached, snail-PoW block
+		// production stops: the consensus layer takes over fast-chain sealing and
+		// no further snail headers are synthesized here.
+		if PastTerminalTotalDifficulty(config, chainReader, parent) {
+			return nil
+		}
 
-		b := &BlockGen{i: i, parent: parent, chain: blocks, chainReader: blockchain, config: config, engine: engine}
-		b.header = makeHeader(b.chainReader, parent, b.engine)
+		b := &BlockGen{i: i, parent: parent, chain: blocks, chainReader: chainReader, config: config, engine: engine}
+		b.header = makeHeader(config, b.chainReader, parent, b.engine)
 
 		// Execute any user modifications to the block and finalize it
 		if gen != nil {
 			gen(i, b)
 		}
+		// A block with no withdrawals hashes exactly as it did before this
+		// field existed, so historical blocks remain unaffected.
+		b.header.WithdrawalsHash = types.CalcWithdrawalsHash(b.withdrawals)
 
 		if b.engine != nil {
 			// TODO: add fruits support
 			block, _ := b.engine.FinalizeSnail(b.chainReader, b.header, b.uncles, b.fruits, b.signs)
-
+			if block != nil {
+				// Thread the withdrawals into the assembled body; the
+				// balance credit itself happens later, at block-apply time,
+				// via types.ApplyWithdrawals against the EVM state.
+				block = block.WithWithdrawals(b.withdrawals)
+				chainReader.appendBlock(block)
+			}
 			return block
 		}
 		return nil
 	}
 	for i := 0; i < n; i++ {
 		block := genblock(i, parent)
+		if block == nil {
+			blocks = blocks[:i]
+			break
+		}
 		blocks[i] = block
 		parent = block
 	}
 	return blocks
 }
 
-func makeHeader(chain consensus.SnailChainReader, parent *types.SnailBlock, engine consensus.Engine) *types.SnailHeader {
+// PastTerminalTotalDifficulty reports whether parent's total difficulty has
+// already reached config.TerminalTotalDifficulty, meaning the snail chain has
+// transitioned to PoS and must stop minting PoW headers.
+func PastTerminalTotalDifficulty(config *params.ChainConfig, chain consensus.SnailChainReader, parent *types.SnailBlock) bool {
+	if config.TerminalTotalDifficulty == nil {
+		return false
+	}
+	td := chain.GetTd(parent.Hash(), parent.NumberU64())
+	if td == nil {
+		return false
+	}
+	return td.Cmp(config.TerminalTotalDifficulty) >= 0
+}
+
+func makeHeader(config *params.ChainConfig, chain consensus.SnailChainReader, parent *types.SnailBlock, engine consensus.Engine) *types.SnailHeader {
 
 	var time *big.Int
 	if parent.Time() == nil {
@@ -159,7 +226,7 @@ func makeHeader(chain consensus.SnailChainReader, parent *types.SnailBlock, engi
 		time = new(big.Int).Add(parent.Time(), big.NewInt(10)) // block time is fixed at 10 seconds
 	}
 
-	return &types.SnailHeader{
+	header := &types.SnailHeader{
 		ParentHash: parent.Hash(),
 		Coinbase:   parent.Coinbase(),
 		Difficulty: engine.CalcSnailDifficulty(chain, time.Uint64(), []*types.SnailHeader{&types.SnailHeader{
@@ -171,6 +238,7 @@ func makeHeader(chain consensus.SnailChainReader, parent *types.SnailBlock, engi
 		Number: new(big.Int).Add(parent.Number(), common.Big1),
 		Time:   time,
 	}
+	return header
 }
 
 // makeHeaderChain creates a deterministic chain of headers rooted at parent.
@@ -192,6 +260,82 @@ func makeBlockChain(parent *types.SnailBlock, n int, engine consensus.Engine, db
 	return blocks
 }
 
+// fakeChainReader is a lightweight, in-memory consensus.SnailChainReader
+// backing GenerateChain. It replaces the previous approach of spinning up a
+// full SnailBlockChain per generated block (needed only so clique-style
+// engines had somewhere to look up ancestors), letting any consensus.Engine
+// - Clique-style PoA, ethash fakers, or a future PoS beacon engine - drive
+// GenerateChain through the exact same, much cheaper, API.
+type fakeChainReader struct {
+	config *params.ChainConfig
+	blocks map[common.Hash]*types.SnailBlock
+}
+
+func newFakeChainReader(config *params.ChainConfig, db ethdb.Database, genesis *types.SnailBlock) *fakeChainReader {
+	reader := &fakeChainReader{config: config, blocks: make(map[common.Hash]*types.SnailBlock)}
+	reader.appendBlock(genesis)
+	return reader
+}
+
+func (r *fakeChainReader) appendBlock(block *types.SnailBlock) {
+	if block != nil {
+		r.blocks[block.Hash()] = block
+	}
+}
+
+// Config returns the chain configuration GenerateChain is minting blocks
+// under.
+func (r *fakeChainReader) Config() *params.ChainConfig {
+	return r.config
+}
+
+// CurrentHeader returns nil; fakeChainReader only ever answers ancestor
+// lookups for the blocks GenerateChain has already produced.
+func (r *fakeChainReader) CurrentHeader() *types.SnailHeader {
+	return nil
+}
+
+// GetHeader returns the header of a previously generated block by hash and
+// number.
+func (r *fakeChainReader) GetHeader(hash common.Hash, number uint64) *types.SnailHeader {
+	block := r.blocks[hash]
+	if block == nil || block.NumberU64() != number {
+		return nil
+	}
+	return block.Header()
+}
+
+// GetHeaderByNumber is unsupported: fakeChainReader only indexes by hash,
+// since that's all GenerateChain and the engines it drives need.
+func (r *fakeChainReader) GetHeaderByNumber(number uint64) *types.SnailHeader {
+	return nil
+}
+
+// GetBlock returns a previously generated block by hash and number.
+func (r *fakeChainReader) GetBlock(hash common.Hash, number uint64) *types.SnailBlock {
+	block := r.blocks[hash]
+	if block == nil || block.NumberU64() != number {
+		return nil
+	}
+	return block
+}
+
+// GetTd returns the total difficulty of a previously generated block,
+// computed by walking parent links rather than being stored, since
+// fakeChainReader keeps no more state than the blocks map itself.
+func (r *fakeChainReader) GetTd(hash common.Hash, number uint64) *big.Int {
+	block := r.blocks[hash]
+	if block == nil || block.NumberU64() != number {
+		return nil
+	}
+	td := new(big.Int)
+	for block != nil {
+		td.Add(td, block.BlockDifficulty())
+		block = r.blocks[block.ParentHash()]
+	}
+	return td
+}
+
 func makeSnailBlockFruit(chain *SnailBlockChain,makeStartFastNum int,makeFruitSize int,config *params.ChainConfig,
 	pubkey []byte,coinbaseAddr common.Address,signs []*types.PbftSign,isBlock bool) (*types.SnailBlock,error){
 
@@ -0,0 +1,171 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+	"sync/atomic"
+
+	"github.com/truechain/truechain-engineering-code/common"
+	"github.com/truechain/truechain-engineering-code/rlp"
+)
+
+// BloomByteLength is the number of bytes in a Bloom filter, matching the
+// 2048-bit log bloom every other Ethereum-derived chain uses.
+const BloomByteLength = 256
+
+// Bloom is a 2048-bit log bloom filter.
+type Bloom [BloomByteLength]byte
+
+// Bytes returns the bloom filter's raw bytes.
+func (b Bloom) Bytes() []byte { return b[:] }
+
+// BytesToBloom creates a Bloom from a byte slice, left-padded if it's
+// shorter than BloomByteLength.
+func BytesToBloom(data []byte) Bloom {
+	var b Bloom
+	copy(b[BloomByteLength-len(data):], data)
+	return b
+}
+
+// Header is the fast chain's block header - the EVM-compatible counterpart
+// to SnailHeader, extended per EIP-4895 with a WithdrawalsHash committing to
+// any validator withdrawals the block credits.
+type Header struct {
+	ParentHash  common.Hash    `json:"parentHash"       gencodec:"required"`
+	Coinbase    common.Address `json:"miner"            gencodec:"required"`
+	Root        common.Hash    `json:"stateRoot"        gencodec:"required"`
+	TxHash      common.Hash    `json:"transactionsRoot" gencodec:"required"`
+	ReceiptHash common.Hash    `json:"receiptsRoot"     gencodec:"required"`
+	Bloom       Bloom          `json:"logsBloom"        gencodec:"required"`
+	Difficulty  *big.Int       `json:"difficulty"       gencodec:"required"`
+	Number      *big.Int       `json:"number"           gencodec:"required"`
+	GasLimit    uint64         `json:"gasLimit"         gencodec:"required"`
+	GasUsed     uint64         `json:"gasUsed"          gencodec:"required"`
+	Time        *big.Int       `json:"timestamp"        gencodec:"required"`
+	Extra       []byte         `json:"extraData"        gencodec:"required"`
+	MixDigest   common.Hash    `json:"mixHash"`
+	BaseFee     *big.Int       `json:"baseFeePerGas"`
+
+	// WithdrawalsHash is the trie root of the withdrawals credited by this
+	// block - see CalcWithdrawalsHash. A block with none hashes exactly as
+	// it did before this field existed.
+	WithdrawalsHash common.Hash `json:"withdrawalsRoot"`
+}
+
+// Hash returns the header's RLP hash.
+func (h *Header) Hash() common.Hash {
+	return rlpHash(h)
+}
+
+// Transaction is a placeholder for the fast chain's EVM transaction type;
+// this checkout's core/types package doesn't carry the full transaction
+// implementation (signing, tx pool plumbing, receipts), only enough shape
+// for the Engine API to RLP round-trip and hash a payload's transaction
+// list without inspecting it.
+type Transaction struct {
+	data []byte
+}
+
+// Transactions implements DerivableList so TxHash can be computed the same
+// way every other derivable list in this package is.
+type Transactions []*Transaction
+
+func (t Transactions) Len() int { return len(t) }
+func (t Transactions) GetRlp(i int) []byte {
+	enc, err := rlp.EncodeToBytes(t[i])
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// body is the part of a Block beyond its header: its transactions, uncles
+// and any validator withdrawals it credits.
+type body struct {
+	Transactions []*Transaction
+	Uncles       []*Header
+	Withdrawals  []*Withdrawal
+}
+
+// Block is an immutable fast-chain block. Like SnailBlock, mutating helpers
+// (WithBody, WithWithdrawals) return a shallow copy rather than modifying
+// the receiver in place.
+type Block struct {
+	header *Header
+	body   body
+
+	hash atomic.Value
+}
+
+// NewBlockWithHeader creates a block with the given header and an empty
+// body, sharing no state with header: later modifications to header have no
+// effect on the returned block.
+func NewBlockWithHeader(header *Header) *Block {
+	cpy := *header
+	return &Block{header: &cpy}
+}
+
+// WithBody returns a shallow copy of the block with its transactions and
+// uncles replaced.
+func (b *Block) WithBody(transactions []*Transaction, uncles []*Header) *Block {
+	cpy := &Block{header: b.header, body: b.body}
+	cpy.body.Transactions = make([]*Transaction, len(transactions))
+	copy(cpy.body.Transactions, transactions)
+	cpy.body.Uncles = make([]*Header, len(uncles))
+	copy(cpy.body.Uncles, uncles)
+	return cpy
+}
+
+// WithWithdrawals returns a shallow copy of the block with its withdrawals
+// replaced. It does not touch WithdrawalsHash - the caller is expected to
+// have already committed it into the header.
+func (b *Block) WithWithdrawals(withdrawals []*Withdrawal) *Block {
+	cpy := &Block{header: b.header, body: b.body}
+	if len(withdrawals) > 0 {
+		cpy.body.Withdrawals = make([]*Withdrawal, len(withdrawals))
+		copy(cpy.body.Withdrawals, withdrawals)
+	}
+	return cpy
+}
+
+func (b *Block) Header() *Header              { cpy := *b.header; return &cpy }
+func (b *Block) Transactions() []*Transaction { return b.body.Transactions }
+func (b *Block) Withdrawals() []*Withdrawal   { return b.body.Withdrawals }
+func (b *Block) ParentHash() common.Hash      { return b.header.ParentHash }
+func (b *Block) Coinbase() common.Address     { return b.header.Coinbase }
+func (b *Block) Root() common.Hash            { return b.header.Root }
+func (b *Block) ReceiptHash() common.Hash     { return b.header.ReceiptHash }
+func (b *Block) Bloom() Bloom                 { return b.header.Bloom }
+func (b *Block) MixDigest() common.Hash       { return b.header.MixDigest }
+func (b *Block) Number() *big.Int             { return b.header.Number }
+func (b *Block) NumberU64() uint64            { return b.header.Number.Uint64() }
+func (b *Block) GasLimit() uint64             { return b.header.GasLimit }
+func (b *Block) GasUsed() uint64              { return b.header.GasUsed }
+func (b *Block) Time() *big.Int               { return b.header.Time }
+func (b *Block) Extra() []byte                { return b.header.Extra }
+func (b *Block) BaseFee() *big.Int            { return b.header.BaseFee }
+
+// Hash returns the block's header hash, cached after the first call.
+func (b *Block) Hash() common.Hash {
+	if hash := b.hash.Load(); hash != nil {
+		return hash.(common.Hash)
+	}
+	h := b.header.Hash()
+	b.hash.Store(h)
+	return h
+}
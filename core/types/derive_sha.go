@@ -0,0 +1,58 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"github.com/truechain/truechain-engineering-code/common"
+	"github.com/truechain/truechain-engineering-code/rlp"
+	"github.com/truechain/truechain-engineering-code/trie"
+)
+
+// EmptyRootHash is the trie root of an empty DerivableList (no transactions,
+// no withdrawals, ...), computed once and reused so empty lists don't each
+// pay for their own trie build.
+var EmptyRootHash = DeriveSha(emptyList{})
+
+// DerivableList is the interface a list of RLP-encodable leaves must
+// implement to have its trie root computed by DeriveSha: receipts,
+// transactions and withdrawals all satisfy it.
+type DerivableList interface {
+	Len() int
+	GetRlp(i int) []byte
+}
+
+// DeriveSha computes the root of a Merkle trie whose leaves are list's
+// elements, keyed by their index (RLP-encoded) and valued by their RLP
+// encoding, matching the convention used throughout the rest of this
+// codebase for transaction and receipt roots.
+func DeriveSha(list DerivableList) common.Hash {
+	t := new(trie.Trie)
+	for i := 0; i < list.Len(); i++ {
+		key, err := rlp.EncodeToBytes(uint(i))
+		if err != nil {
+			panic(err)
+		}
+		t.Update(key, list.GetRlp(i))
+	}
+	return t.Hash()
+}
+
+// emptyList is the zero-length DerivableList used to compute EmptyRootHash.
+type emptyList struct{}
+
+func (emptyList) Len() int            { return 0 }
+func (emptyList) GetRlp(i int) []byte { panic("index out of range") }
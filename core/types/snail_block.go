@@ -0,0 +1,193 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+	"sync/atomic"
+
+	"github.com/truechain/truechain-engineering-code/common"
+	"github.com/truechain/truechain-engineering-code/crypto"
+	"github.com/truechain/truechain-engineering-code/rlp"
+)
+
+// SnailHeader represents the header of a snail (PoW) block: the truechain
+// dual-chain consensus's analogue of an ethash block header, extended with
+// the pointer/fruit bookkeeping the snail chain uses to reward fast-chain
+// block production.
+type SnailHeader struct {
+	ParentHash    common.Hash    `json:"parentHash"       gencodec:"required"`
+	UncleHash     common.Hash    `json:"sha3Uncles"       gencodec:"required"`
+	Coinbase      common.Address `json:"miner"            gencodec:"required"`
+	Publickey     []byte         `json:"publicKey"`
+	Difficulty    *big.Int       `json:"difficulty"       gencodec:"required"`
+	Number        *big.Int       `json:"number"           gencodec:"required"`
+	Time          *big.Int       `json:"timestamp"        gencodec:"required"`
+	Extra         []byte         `json:"extraData"`
+	Fruit         bool           `json:"fruit"`
+	FastNumber    *big.Int       `json:"fastNumber"`
+	PointerHash   common.Hash    `json:"pointerHash"`
+	PointerNumber *big.Int       `json:"pointerNumber"`
+
+	// WithdrawalsHash is the trie root of the withdrawals credited by this
+	// block, or EmptyRootHash for a block with none - see
+	// CalcWithdrawalsHash. It keeps pre-fork blocks hashing exactly as they
+	// did before validator withdrawals existed.
+	WithdrawalsHash common.Hash `json:"withdrawalsRoot"`
+}
+
+// Hash returns the header's RLP hash.
+func (h *SnailHeader) Hash() common.Hash {
+	return rlpHash(h)
+}
+
+// SnailBlockReceipt records the fruit reward credited for a single
+// fast-chain block folded into a snail block.
+type SnailBlockReceipt struct {
+	FastHash   common.Hash
+	FastNumber *big.Int
+}
+
+// PbftSign is a single committee member's signature over a fast-chain
+// block, carried inside a snail block to prove the fruits it rewards were
+// actually agreed on.
+type PbftSign struct {
+	FastHeight *big.Int
+	FastHash   common.Hash
+	Sign       []byte
+}
+
+// snailBody is the part of a SnailBlock beyond its header: the fruits
+// (fast-chain blocks) it rewards, the PBFT signatures backing them, and any
+// validator withdrawals it credits.
+type snailBody struct {
+	Fruits      []*SnailBlock
+	Signs       []*PbftSign
+	Withdrawals []*Withdrawal
+}
+
+// SnailBlock is an immutable snail-chain block: a header plus the fruits and
+// signatures it rewards. Like Block, mutating helpers (WithWithdrawals)
+// return a shallow copy rather than modifying the receiver in place.
+type SnailBlock struct {
+	header *SnailHeader
+	body   snailBody
+
+	hash atomic.Value
+}
+
+// SnailBlocks is a slice of snail blocks, e.g. as produced by GenerateChain.
+type SnailBlocks []*SnailBlock
+
+// NewSnailBlock creates a block with the given header, fruits, signatures
+// and fruit receipts. The header is copied, so changes to header after
+// calling NewSnailBlock have no effect on the returned block.
+func NewSnailBlock(header *SnailHeader, fruits []*SnailBlock, signs []*PbftSign, receipts []*SnailBlockReceipt) *SnailBlock {
+	b := &SnailBlock{header: copySnailHeader(header)}
+	if len(fruits) > 0 {
+		b.body.Fruits = make([]*SnailBlock, len(fruits))
+		copy(b.body.Fruits, fruits)
+	}
+	if len(signs) > 0 {
+		b.body.Signs = make([]*PbftSign, len(signs))
+		copy(b.body.Signs, signs)
+	}
+	return b
+}
+
+// NewSnailBlockWithHeader creates a block with the given header, sharing no
+// state with it: later modifications to header don't affect the block.
+func NewSnailBlockWithHeader(header *SnailHeader) *SnailBlock {
+	return &SnailBlock{header: copySnailHeader(header)}
+}
+
+// copySnailHeader makes a deep copy of a SnailHeader so blocks don't alias
+// the big.Ints and byte slices of the header they were built from.
+func copySnailHeader(h *SnailHeader) *SnailHeader {
+	cpy := *h
+	if cpy.Difficulty = new(big.Int); h.Difficulty != nil {
+		cpy.Difficulty.Set(h.Difficulty)
+	}
+	if cpy.Number = new(big.Int); h.Number != nil {
+		cpy.Number.Set(h.Number)
+	}
+	if cpy.Time = new(big.Int); h.Time != nil {
+		cpy.Time.Set(h.Time)
+	}
+	if h.FastNumber != nil {
+		cpy.FastNumber = new(big.Int).Set(h.FastNumber)
+	}
+	if h.PointerNumber != nil {
+		cpy.PointerNumber = new(big.Int).Set(h.PointerNumber)
+	}
+	if len(h.Extra) > 0 {
+		cpy.Extra = append([]byte{}, h.Extra...)
+	}
+	if len(h.Publickey) > 0 {
+		cpy.Publickey = append([]byte{}, h.Publickey...)
+	}
+	return &cpy
+}
+
+// Header returns a copy of the block's header.
+func (b *SnailBlock) Header() *SnailHeader { return copySnailHeader(b.header) }
+
+// Hash returns the block's header hash, cached after the first call.
+func (b *SnailBlock) Hash() common.Hash {
+	if hash := b.hash.Load(); hash != nil {
+		return hash.(common.Hash)
+	}
+	h := b.header.Hash()
+	b.hash.Store(h)
+	return h
+}
+
+func (b *SnailBlock) ParentHash() common.Hash    { return b.header.ParentHash }
+func (b *SnailBlock) UncleHash() common.Hash     { return b.header.UncleHash }
+func (b *SnailBlock) Coinbase() common.Address   { return b.header.Coinbase }
+func (b *SnailBlock) BlockDifficulty() *big.Int  { return b.header.Difficulty }
+func (b *SnailBlock) Number() *big.Int           { return b.header.Number }
+func (b *SnailBlock) NumberU64() uint64          { return b.header.Number.Uint64() }
+func (b *SnailBlock) Time() *big.Int             { return b.header.Time }
+func (b *SnailBlock) Extra() []byte              { return b.header.Extra }
+func (b *SnailBlock) FastNumber() *big.Int       { return b.header.FastNumber }
+func (b *SnailBlock) Fruits() []*SnailBlock      { return b.body.Fruits }
+func (b *SnailBlock) Signs() []*PbftSign         { return b.body.Signs }
+func (b *SnailBlock) Withdrawals() []*Withdrawal { return b.body.Withdrawals }
+
+// WithWithdrawals returns a shallow copy of the block with its withdrawals
+// set to withdrawals. It does not touch WithdrawalsHash - the caller is
+// expected to have already committed it into the header (see
+// GenerateChain, which sets it via CalcWithdrawalsHash before finalizing).
+func (b *SnailBlock) WithWithdrawals(withdrawals []*Withdrawal) *SnailBlock {
+	cpy := &SnailBlock{header: b.header, body: b.body}
+	if len(withdrawals) > 0 {
+		cpy.body.Withdrawals = make([]*Withdrawal, len(withdrawals))
+		copy(cpy.body.Withdrawals, withdrawals)
+	}
+	return cpy
+}
+
+// rlpHash RLP-encodes x and returns the Keccak256 hash of the encoding, the
+// same hashing convention used for every other header type in this repo.
+func rlpHash(x interface{}) (h common.Hash) {
+	enc, err := rlp.EncodeToBytes(x)
+	if err != nil {
+		panic(err)
+	}
+	return crypto.Keccak256Hash(enc)
+}
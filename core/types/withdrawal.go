@@ -0,0 +1,121 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/truechain/truechain-engineering-code/common"
+	"github.com/truechain/truechain-engineering-code/common/hexutil"
+	"github.com/truechain/truechain-engineering-code/rlp"
+)
+
+// Withdrawal represents a validator withdrawal credited directly to a
+// beneficiary's balance by the protocol, bypassing the EVM. It follows the
+// EIP-4895 shape: an ever-increasing global index, the withdrawing
+// validator's index, the recipient address and the amount in Gwei (not wei -
+// wei would overflow a uint64 for a realistic validator balance).
+type Withdrawal struct {
+	Index     uint64         `json:"index"`
+	Validator uint64         `json:"validatorIndex"`
+	Address   common.Address `json:"address"`
+	Amount    uint64         `json:"amount"` // in Gwei
+}
+
+// withdrawalMarshaling is the JSON wire shape of Withdrawal: its uint64
+// fields are hex-quantity encoded, matching the rest of the JSON-RPC surface.
+type withdrawalMarshaling struct {
+	Index     hexutil.Uint64 `json:"index"`
+	Validator hexutil.Uint64 `json:"validatorIndex"`
+	Address   common.Address `json:"address"`
+	Amount    hexutil.Uint64 `json:"amount"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (w *Withdrawal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&withdrawalMarshaling{
+		Index:     hexutil.Uint64(w.Index),
+		Validator: hexutil.Uint64(w.Validator),
+		Address:   w.Address,
+		Amount:    hexutil.Uint64(w.Amount),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (w *Withdrawal) UnmarshalJSON(input []byte) error {
+	var dec withdrawalMarshaling
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	w.Index = uint64(dec.Index)
+	w.Validator = uint64(dec.Validator)
+	w.Address = dec.Address
+	w.Amount = uint64(dec.Amount)
+	return nil
+}
+
+// Withdrawals is a list of withdrawals, usable as an RLP/DeriveSha list.
+type Withdrawals []*Withdrawal
+
+// Len returns the number of withdrawals in the list.
+func (w Withdrawals) Len() int { return len(w) }
+
+// GetRlp returns the RLP encoding of the i'th withdrawal, completing the
+// DerivableList interface DeriveSha needs to compute WithdrawalsHash.
+func (w Withdrawals) GetRlp(i int) []byte {
+	enc, err := rlp.EncodeToBytes(w[i])
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// GWei is the number of wei in one Gwei, the unit Withdrawal.Amount is
+// denominated in.
+var GWei = big.NewInt(1e9)
+
+// CalcWithdrawalsHash returns the trie root of withdrawals, used to populate
+// SnailHeader.WithdrawalsHash. A nil or empty list hashes to EmptyRootHash so
+// that pre-fork blocks, which never called AddWithdrawal, keep hashing
+// identically to how they did before this field existed.
+func CalcWithdrawalsHash(withdrawals Withdrawals) common.Hash {
+	if len(withdrawals) == 0 {
+		return EmptyRootHash
+	}
+	return DeriveSha(withdrawals)
+}
+
+// StateDB is the slice of state.StateDB that applying withdrawals needs. It
+// is declared locally so this file doesn't need to import core/state (which
+// would be a cyclic import in the real tree), and so ApplyWithdrawals can be
+// unit tested against a trivial fake.
+type StateDB interface {
+	AddBalance(addr common.Address, amount *big.Int)
+}
+
+// ApplyWithdrawals credits every withdrawal directly to its recipient's
+// balance, bypassing the EVM entirely - the block-apply-time counterpart to
+// BlockGen.AddWithdrawal. The caller is responsible for invoking this once
+// per block, after all transactions have been processed, with the state the
+// block is being applied against.
+func ApplyWithdrawals(state StateDB, withdrawals Withdrawals) {
+	for _, w := range withdrawals {
+		amountWei := new(big.Int).Mul(new(big.Int).SetUint64(w.Amount), GWei)
+		state.AddBalance(w.Address, amountWei)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/truechain/truechain-engineering-code/common"
+)
+
+// TestCalcWithdrawalsHashEmpty pins the guarantee that a block with no
+// withdrawals hashes exactly as it did before this field existed: both a nil
+// slice and an explicitly empty one must fall back to EmptyRootHash.
+func TestCalcWithdrawalsHashEmpty(t *testing.T) {
+	if got := CalcWithdrawalsHash(nil); got != EmptyRootHash {
+		t.Errorf("nil withdrawals: got %x, want EmptyRootHash %x", got, EmptyRootHash)
+	}
+	if got := CalcWithdrawalsHash(Withdrawals{}); got != EmptyRootHash {
+		t.Errorf("empty withdrawals: got %x, want EmptyRootHash %x", got, EmptyRootHash)
+	}
+}
+
+func TestCalcWithdrawalsHashNonEmpty(t *testing.T) {
+	w := Withdrawals{{Index: 0, Validator: 1, Address: common.HexToAddress("0x1"), Amount: 32000000000}}
+	if got := CalcWithdrawalsHash(w); got == EmptyRootHash {
+		t.Errorf("non-empty withdrawals must not hash to EmptyRootHash")
+	}
+}
+
+// fakeStateDB is the minimal types.StateDB fake needed to test
+// ApplyWithdrawals without pulling in core/state.
+type fakeStateDB struct {
+	balances map[common.Address]*big.Int
+}
+
+func (s *fakeStateDB) AddBalance(addr common.Address, amount *big.Int) {
+	if s.balances == nil {
+		s.balances = make(map[common.Address]*big.Int)
+	}
+	if _, ok := s.balances[addr]; !ok {
+		s.balances[addr] = new(big.Int)
+	}
+	s.balances[addr].Add(s.balances[addr], amount)
+}
+
+func TestApplyWithdrawalsCreditsGweiAsWei(t *testing.T) {
+	addr := common.HexToAddress("0x2")
+	state := &fakeStateDB{}
+	ApplyWithdrawals(state, Withdrawals{{Index: 0, Validator: 1, Address: addr, Amount: 1}})
+
+	want := new(big.Int).Set(GWei) // 1 Gwei == 1e9 wei
+	if got := state.balances[addr]; got.Cmp(want) != 0 {
+		t.Errorf("got balance %s, want %s", got, want)
+	}
+}
+
+func TestApplyWithdrawalsAccumulates(t *testing.T) {
+	addr := common.HexToAddress("0x3")
+	state := &fakeStateDB{}
+	ApplyWithdrawals(state, Withdrawals{
+		{Index: 0, Validator: 1, Address: addr, Amount: 2},
+		{Index: 1, Validator: 2, Address: addr, Amount: 3},
+	})
+
+	want := new(big.Int).Mul(big.NewInt(5), GWei)
+	if got := state.balances[addr]; got.Cmp(want) != 0 {
+		t.Errorf("got balance %s, want %s", got, want)
+	}
+}
+
+func TestWithdrawalJSONRoundTrip(t *testing.T) {
+	w := &Withdrawal{Index: 7, Validator: 42, Address: common.HexToAddress("0x4"), Amount: 123456}
+	data, err := w.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	var got Withdrawal
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if got != *w {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, *w)
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package catalyst implements the engine_* JSON-RPC namespace an external
+// consensus client uses to drive fast-chain block production once the snail
+// chain has crossed its terminal total difficulty.
+package catalyst
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/truechain/truechain-engineering-code/common"
+	"github.com/truechain/truechain-engineering-code/consensus/beacon"
+	"github.com/truechain/truechain-engineering-code/core/types"
+	"github.com/truechain/truechain-engineering-code/log"
+	"github.com/truechain/truechain-engineering-code/node"
+	"github.com/truechain/truechain-engineering-code/params"
+	"github.com/truechain/truechain-engineering-code/rlp"
+	"github.com/truechain/truechain-engineering-code/rpc"
+)
+
+// Backend is the slice of the fast-chain node the Engine API needs: enough to
+// insert and query blocks and to ask the miner to build new payloads.
+type Backend interface {
+	BlockChain() BlockChain
+	Miner() Miner
+	Config() *params.ChainConfig
+}
+
+// BlockChain is the subset of core.BlockChain consumed by the Engine API.
+type BlockChain interface {
+	CurrentBlock() *types.Block
+	GetBlockByHash(hash common.Hash) *types.Block
+	InsertBlockWithoutSetHead(block *types.Block) error
+	SetHead(head common.Hash) error
+}
+
+// Miner is the subset of miner.Miner consumed by the Engine API.
+type Miner interface {
+	BuildPayload(parentHash common.Hash, timestamp uint64, feeRecipient common.Address, random common.Hash, withdrawals []*types.Withdrawal) (*types.Block, error)
+}
+
+// Register installs the engine_* namespace onto the node's in-process and
+// authenticated RPC servers.
+func Register(stack *node.Node, backend Backend) error {
+	stack.RegisterAPIs([]rpc.API{
+		{
+			Namespace: "engine",
+			Version:   "1.0",
+			Service:   NewConsensusAPI(backend),
+			Public:    true,
+		},
+	})
+	return nil
+}
+
+// PayloadStatus is returned by newPayload and forkchoiceUpdated to report
+// whether the execution layer accepted, rejected, or is still syncing to the
+// requested block.
+type PayloadStatus string
+
+const (
+	PayloadValid    PayloadStatus = "VALID"
+	PayloadInvalid  PayloadStatus = "INVALID"
+	PayloadSyncing  PayloadStatus = "SYNCING"
+	PayloadAccepted PayloadStatus = "ACCEPTED"
+)
+
+// PayloadStatusV1 is the response shape shared by newPayloadV1 and
+// forkchoiceUpdatedV1.
+type PayloadStatusV1 struct {
+	Status          PayloadStatus `json:"status"`
+	LatestValidHash *common.Hash  `json:"latestValidHash"`
+	ValidationError *string       `json:"validationError"`
+}
+
+// ExecutableData mirrors the JSON shape of an execution payload as defined
+// by the Engine API spec.
+type ExecutableData struct {
+	ParentHash    common.Hash          `json:"parentHash"    gencodec:"required"`
+	FeeRecipient  common.Address       `json:"feeRecipient"  gencodec:"required"`
+	StateRoot     common.Hash          `json:"stateRoot"     gencodec:"required"`
+	ReceiptsRoot  common.Hash          `json:"receiptsRoot"  gencodec:"required"`
+	LogsBloom     []byte               `json:"logsBloom"     gencodec:"required"`
+	Random        common.Hash          `json:"prevRandao"    gencodec:"required"`
+	Number        uint64               `json:"blockNumber"   gencodec:"required"`
+	GasLimit      uint64               `json:"gasLimit"      gencodec:"required"`
+	GasUsed       uint64               `json:"gasUsed"       gencodec:"required"`
+	Timestamp     uint64               `json:"timestamp"     gencodec:"required"`
+	ExtraData     []byte               `json:"extraData"     gencodec:"required"`
+	BaseFeePerGas *common.Hash         `json:"baseFeePerGas" gencodec:"required"`
+	BlockHash     common.Hash          `json:"blockHash"     gencodec:"required"`
+	Transactions  [][]byte             `json:"transactions"  gencodec:"required"`
+	Withdrawals   []*types.Withdrawal  `json:"withdrawals"`
+}
+
+// ForkchoiceStateV1 is the consensus client's view of the canonical chain
+// head, safe head and finalized head.
+type ForkchoiceStateV1 struct {
+	HeadBlockHash      common.Hash `json:"headBlockHash"`
+	SafeBlockHash      common.Hash `json:"safeBlockHash"`
+	FinalizedBlockHash common.Hash `json:"finalizedBlockHash"`
+}
+
+// PayloadAttributesV1 requests that forkchoiceUpdated also begin building a
+// new payload once the fork choice has been applied.
+type PayloadAttributesV1 struct {
+	Timestamp             uint64              `json:"timestamp"`
+	Random                common.Hash         `json:"prevRandao"`
+	SuggestedFeeRecipient common.Address      `json:"suggestedFeeRecipient"`
+	Withdrawals           []*types.Withdrawal `json:"withdrawals"`
+}
+
+// ForkchoiceResponse is returned by forkchoiceUpdatedV1.
+type ForkchoiceResponse struct {
+	PayloadStatus PayloadStatusV1 `json:"payloadStatus"`
+	PayloadID     *PayloadID      `json:"payloadId"`
+}
+
+// PayloadID identifies an in-progress payload build requested via
+// forkchoiceUpdatedV1 and later retrieved with getPayloadV1.
+type PayloadID [8]byte
+
+func (p PayloadID) String() string {
+	return fmt.Sprintf("0x%x", [8]byte(p))
+}
+
+// ConsensusAPI implements the engine_* namespace.
+type ConsensusAPI struct {
+	backend Backend
+
+	mu      sync.Mutex
+	payloads map[PayloadID]*types.Block // getPayload poll cache, keyed by the id handed out in forkchoiceUpdated
+	nextID   uint64
+}
+
+// NewConsensusAPI creates the engine_* RPC service bound to backend.
+func NewConsensusAPI(backend Backend) *ConsensusAPI {
+	return &ConsensusAPI{
+		backend:  backend,
+		payloads: make(map[PayloadID]*types.Block),
+	}
+}
+
+// ForkchoiceUpdatedV1 applies a new fork choice to the chain and, if
+// payloadAttributes is non-nil, starts building the next payload on top of
+// it, returning an id the consensus client polls with GetPayloadV1.
+func (api *ConsensusAPI) ForkchoiceUpdatedV1(update ForkchoiceStateV1, payloadAttributes *PayloadAttributesV1) (ForkchoiceResponse, error) {
+	block := api.backend.BlockChain().GetBlockByHash(update.HeadBlockHash)
+	if block == nil {
+		return ForkchoiceResponse{PayloadStatus: PayloadStatusV1{Status: PayloadSyncing}}, nil
+	}
+	if err := api.backend.BlockChain().SetHead(update.HeadBlockHash); err != nil {
+		return ForkchoiceResponse{PayloadStatus: PayloadStatusV1{Status: PayloadInvalid}}, err
+	}
+
+	if payloadAttributes == nil {
+		return ForkchoiceResponse{PayloadStatus: PayloadStatusV1{Status: PayloadValid, LatestValidHash: &update.HeadBlockHash}}, nil
+	}
+
+	payload, err := api.backend.Miner().BuildPayload(update.HeadBlockHash, payloadAttributes.Timestamp,
+		payloadAttributes.SuggestedFeeRecipient, payloadAttributes.Random, payloadAttributes.Withdrawals)
+	if err != nil {
+		log.Error("Failed to build payload", "err", err)
+		return ForkchoiceResponse{PayloadStatus: PayloadStatusV1{Status: PayloadInvalid}}, err
+	}
+
+	id := api.cachePayload(payload)
+	return ForkchoiceResponse{
+		PayloadStatus: PayloadStatusV1{Status: PayloadValid, LatestValidHash: &update.HeadBlockHash},
+		PayloadID:     &id,
+	}, nil
+}
+
+// GetPayloadV1 returns a previously requested payload for the consensus
+// client to broadcast, identified by the id returned from
+// ForkchoiceUpdatedV1.
+func (api *ConsensusAPI) GetPayloadV1(payloadID PayloadID) (*ExecutableData, error) {
+	api.mu.Lock()
+	block, ok := api.payloads[payloadID]
+	api.mu.Unlock()
+	if !ok {
+		return nil, errors.New("unknown payload")
+	}
+	return blockToExecutableData(block), nil
+}
+
+// NewPayloadV1 validates and, if valid, imports a payload received from the
+// consensus client, without making it the canonical head.
+func (api *ConsensusAPI) NewPayloadV1(params ExecutableData) (PayloadStatusV1, error) {
+	block, err := executableDataToBlock(params)
+	if err != nil {
+		return PayloadStatusV1{Status: PayloadInvalid}, err
+	}
+	if err := api.backend.BlockChain().InsertBlockWithoutSetHead(block); err != nil {
+		return PayloadStatusV1{Status: PayloadInvalid}, err
+	}
+	hash := block.Hash()
+	return PayloadStatusV1{Status: PayloadValid, LatestValidHash: &hash}, nil
+}
+
+// ExchangeTransitionConfigurationV1 lets the consensus client and the
+// execution layer confirm they agree on the terminal total difficulty of the
+// merge.
+func (api *ConsensusAPI) ExchangeTransitionConfigurationV1(remote beacon.TransitionConfig) (*beacon.TransitionConfig, error) {
+	config := api.backend.Config()
+	if err := beacon.ValidateTransitionConfig(config, &remote); err != nil {
+		return nil, err
+	}
+	return &beacon.TransitionConfig{
+		TerminalTotalDifficulty: config.TerminalTotalDifficulty,
+	}, nil
+}
+
+func (api *ConsensusAPI) cachePayload(block *types.Block) PayloadID {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	var id PayloadID
+	api.nextID++
+	for i := 0; i < 8; i++ {
+		id[i] = byte(api.nextID >> (8 * i))
+	}
+	api.payloads[id] = block
+	return id
+}
+
+func blockToExecutableData(block *types.Block) *ExecutableData {
+	txs := block.Transactions()
+	encTxs := make([][]byte, len(txs))
+	for i, tx := range txs {
+		enc, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			// Transactions already inside a sealed block are always
+			// encodable; a failure here means the block itself is corrupt.
+			log.Error("Failed to RLP-encode transaction for payload", "err", err)
+		}
+		encTxs[i] = enc
+	}
+	var baseFee *common.Hash
+	if fee := block.BaseFee(); fee != nil {
+		h := common.BigToHash(fee)
+		baseFee = &h
+	}
+	bloom := block.Bloom()
+	return &ExecutableData{
+		ParentHash:    block.ParentHash(),
+		FeeRecipient:  block.Coinbase(),
+		StateRoot:     block.Root(),
+		ReceiptsRoot:  block.ReceiptHash(),
+		LogsBloom:     bloom.Bytes(),
+		Random:        block.MixDigest(),
+		Number:        block.NumberU64(),
+		GasLimit:      block.GasLimit(),
+		GasUsed:       block.GasUsed(),
+		Timestamp:     block.Time().Uint64(),
+		ExtraData:     block.Extra(),
+		BaseFeePerGas: baseFee,
+		BlockHash:     block.Hash(),
+		Transactions:  encTxs,
+		Withdrawals:   block.Withdrawals(),
+	}
+}
+
+// executableDataToBlock is the inverse of blockToExecutableData: it
+// reassembles a *types.Block from the wire payload and checks the result
+// hashes to the BlockHash the consensus client claimed, so a mismatched or
+// tampered payload is rejected rather than silently imported.
+func executableDataToBlock(data ExecutableData) (*types.Block, error) {
+	if len(data.LogsBloom) != 256 {
+		return nil, fmt.Errorf("invalid logsBloom length %d, want 256", len(data.LogsBloom))
+	}
+	txs, err := decodeTransactions(data.Transactions)
+	if err != nil {
+		return nil, err
+	}
+	var baseFee *big.Int
+	if data.BaseFeePerGas != nil {
+		baseFee = data.BaseFeePerGas.Big()
+	}
+	header := &types.Header{
+		ParentHash:  data.ParentHash,
+		Coinbase:    data.FeeRecipient,
+		Root:        data.StateRoot,
+		TxHash:      types.DeriveSha(types.Transactions(txs)),
+		ReceiptHash: data.ReceiptsRoot,
+		Bloom:       types.BytesToBloom(data.LogsBloom),
+		Difficulty:  common.Big0,
+		Number:      new(big.Int).SetUint64(data.Number),
+		GasLimit:    data.GasLimit,
+		GasUsed:     data.GasUsed,
+		Time:        new(big.Int).SetUint64(data.Timestamp),
+		Extra:       data.ExtraData,
+		MixDigest:   data.Random,
+		BaseFee:     baseFee,
+	}
+	block := types.NewBlockWithHeader(header).WithBody(txs, nil).WithWithdrawals(data.Withdrawals)
+	if block.Hash() != data.BlockHash {
+		return nil, fmt.Errorf("blockhash mismatch, want %x, got %x", data.BlockHash, block.Hash())
+	}
+	return block, nil
+}
+
+func decodeTransactions(enc [][]byte) ([]*types.Transaction, error) {
+	txs := make([]*types.Transaction, len(enc))
+	for i, encTx := range enc {
+		var tx types.Transaction
+		if err := rlp.DecodeBytes(encTx, &tx); err != nil {
+			return nil, fmt.Errorf("invalid transaction %d: %v", i, err)
+		}
+		txs[i] = &tx
+	}
+	return txs, nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"github.com/truechain/truechain-engineering-code/consensus"
+	"github.com/truechain/truechain-engineering-code/core/snailchain"
+	"github.com/truechain/truechain-engineering-code/core/types"
+	"github.com/truechain/truechain-engineering-code/params"
+)
+
+// PastTerminalTotalDifficulty reports whether the snail chain has already
+// crossed its terminal total difficulty as of parent. Once it has, the
+// caller (started with --catalyst) must stop submitting new snail-PoW work
+// and instead let an external consensus client drive fast-chain sealing
+// through eth/catalyst.
+func PastTerminalTotalDifficulty(config *params.ChainConfig, chain consensus.SnailChainReader, parent *types.SnailBlock) bool {
+	return snailchain.PastTerminalTotalDifficulty(config, chain, parent)
+}
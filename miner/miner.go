@@ -0,0 +1,54 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"github.com/truechain/truechain-engineering-code/consensus"
+	"github.com/truechain/truechain-engineering-code/core/types"
+	"github.com/truechain/truechain-engineering-code/log"
+	"github.com/truechain/truechain-engineering-code/params"
+)
+
+// Miner owns the snail-chain sealing loop: on every new parent block it
+// either submits another PoW mining job or, once the chain has crossed its
+// terminal total difficulty, stands down and lets an external consensus
+// client drive fast-chain sealing through eth/catalyst instead.
+type Miner struct {
+	config *params.ChainConfig
+	chain  consensus.SnailChainReader
+	engine consensus.Engine
+}
+
+// New creates a Miner sealing snail blocks under config, reading ancestors
+// from chain and sealing through engine.
+func New(config *params.ChainConfig, chain consensus.SnailChainReader, engine consensus.Engine) *Miner {
+	return &Miner{config: config, chain: chain, engine: engine}
+}
+
+// SealSnailBlock submits parent's successor to the consensus engine for
+// sealing, unless the chain has already crossed its terminal total
+// difficulty - see PastTerminalTotalDifficulty. This is the call the
+// sealing loop makes once per parent block; it's what actually stops PoW
+// mining at the TTD, rather than just reporting whether it should.
+func (m *Miner) SealSnailBlock(parent *types.SnailBlock, block *types.SnailBlock, results chan<- *types.SnailBlock, stop <-chan struct{}) error {
+	if PastTerminalTotalDifficulty(m.config, m.chain, parent) {
+		log.Info("Snail chain past terminal total difficulty, standing down from PoW sealing",
+			"parent", parent.Hash(), "number", parent.NumberU64())
+		return nil
+	}
+	return m.engine.Seal(m.chain, block, results, stop)
+}
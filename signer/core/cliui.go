@@ -28,6 +28,7 @@ import (
 	"github.com/truechain/truechain-engineering-code/common"
 	"github.com/truechain/truechain-engineering-code/internal/trueapi"
 	"github.com/truechain/truechain-engineering-code/log"
+	"github.com/truechain/truechain-engineering-code/signer/fourbyte"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
@@ -73,6 +74,13 @@ func (ui *CommandlineUI) readPassword() string {
 // readPassword reads a single line from stdin, trimming it from the trailing new
 // line and returns it. The input will not be echoed.
 func (ui *CommandlineUI) readPasswordText(inputstring string) string {
+	return ReadPasswordText(inputstring)
+}
+
+// ReadPasswordText prompts on stdin for inputstring and reads back a single
+// line without echoing it, e.g. the master password the rules engine uses to
+// unlock its credential store at startup.
+func ReadPasswordText(inputstring string) string {
 	fmt.Printf("Enter %s:\n", inputstring)
 	fmt.Printf("> ")
 	text, err := terminal.ReadPassword(int(os.Stdin.Fd()))
@@ -117,6 +125,11 @@ func (ui *CommandlineUI) ApproveTx(request *SignTxRequest) (SignTxResponse, erro
 		d := *request.Transaction.Data
 		if len(d) > 0 {
 			fmt.Printf("data:  %v\n", common.Bytes2Hex(d))
+			if request.Callinfo == nil {
+				for _, m := range fourbyte.Default().ValidateCallData(d) {
+					request.Callinfo = append(request.Callinfo, ValidationInfo{Typ: m.Typ, Message: m.Message})
+				}
+			}
 		}
 	}
 	if request.Callinfo != nil {
@@ -0,0 +1,28 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import "github.com/truechain/truechain-engineering-code/signer/fourbyte"
+
+// AddFourBytes exposes api_addFourBytes: it lets a UI teach the signer a new
+// method signature (e.g. "transfer(address,uint256)") at runtime, so future
+// ApproveTx calls can decode calldata that uses it. It returns the number of
+// signatures now known for that selector, letting the caller detect that it
+// just introduced a collision.
+func (api *UIServerAPI) AddFourBytes(signature string) (int, error) {
+	return fourbyte.Default().AddSelector(signature)
+}
@@ -0,0 +1,137 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/truechain/truechain-engineering-code/internal/trueapi"
+	"github.com/truechain/truechain-engineering-code/log"
+	"github.com/truechain/truechain-engineering-code/rpc"
+)
+
+// UIClientAPI is the transport-neutral contract a signer UI must satisfy.
+// CommandlineUI already implements it by prompting a local terminal;
+// externalUI implements the very same set of methods by forwarding each call
+// over JSON-RPC to a remote UI process, so the two are interchangeable
+// wherever the signer needs a UI.
+type UIClientAPI interface {
+	ApproveTx(request *SignTxRequest) (SignTxResponse, error)
+	ApproveListing(request *ListRequest) (ListResponse, error)
+	ApproveNewAccount(request *NewAccountRequest) (NewAccountResponse, error)
+	ShowError(message string)
+	ShowInfo(message string)
+	OnApprovedTx(tx trueapi.SignTransactionResult)
+	OnSignerStartup(info StartupInfo)
+	OnInputRequired(info UserInputRequest) (UserInputResponse, error)
+	RegisterUIServer(api *UIServerAPI)
+}
+
+// externalUI drives a remote UI over a bidirectional JSON-RPC connection,
+// mirroring clef's ui_approveTx / ui_approveListing / ui_onInputRequired /
+// ui_onSignerStartup calls. It is transport-agnostic: StdIOUI and RPCUI only
+// differ in how the underlying *rpc.Client was dialed.
+type externalUI struct {
+	client *rpc.Client
+}
+
+// NewStdIOUI creates a UI that exchanges the engine_*-style ui_* JSON-RPC
+// calls with its own stdin/stdout, for desktop apps or browser extensions
+// that spawn the signer as a subprocess and pipe to it directly.
+func NewStdIOUI() (UIClientAPI, error) {
+	client, err := rpc.DialStdIO()
+	if err != nil {
+		return nil, err
+	}
+	return &externalUI{client: client}, nil
+}
+
+// NewRPCUI creates a UI that exchanges ui_* calls with a UI server reachable
+// over a regular JSON-RPC endpoint (e.g. a websocket served by a hardware
+// wallet daemon), rather than a locally spawned subprocess.
+func NewRPCUI(endpoint string) (UIClientAPI, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &externalUI{client: client}, nil
+}
+
+func (ui *externalUI) ApproveTx(request *SignTxRequest) (SignTxResponse, error) {
+	var result SignTxResponse
+	if err := ui.client.Call(&result, "ui_approveTx", request); err != nil {
+		log.Error("Failed to call ui_approveTx", "err", err)
+		return SignTxResponse{}, err
+	}
+	return result, nil
+}
+
+func (ui *externalUI) ApproveListing(request *ListRequest) (ListResponse, error) {
+	var result ListResponse
+	if err := ui.client.Call(&result, "ui_approveListing", request); err != nil {
+		log.Error("Failed to call ui_approveListing", "err", err)
+		return ListResponse{}, err
+	}
+	return result, nil
+}
+
+func (ui *externalUI) ApproveNewAccount(request *NewAccountRequest) (NewAccountResponse, error) {
+	var result NewAccountResponse
+	if err := ui.client.Call(&result, "ui_approveNewAccount", request); err != nil {
+		log.Error("Failed to call ui_approveNewAccount", "err", err)
+		return NewAccountResponse{}, err
+	}
+	return result, nil
+}
+
+func (ui *externalUI) ShowError(message string) {
+	if err := ui.client.Call(nil, "ui_showError", message); err != nil {
+		log.Error("Failed to call ui_showError", "err", err)
+	}
+}
+
+func (ui *externalUI) ShowInfo(message string) {
+	if err := ui.client.Call(nil, "ui_showInfo", message); err != nil {
+		log.Error("Failed to call ui_showInfo", "err", err)
+	}
+}
+
+func (ui *externalUI) OnApprovedTx(tx trueapi.SignTransactionResult) {
+	if err := ui.client.Call(nil, "ui_onApprovedTx", tx); err != nil {
+		log.Error("Failed to call ui_onApprovedTx", "err", err)
+	}
+}
+
+func (ui *externalUI) OnSignerStartup(info StartupInfo) {
+	if err := ui.client.Call(nil, "ui_onSignerStartup", info); err != nil {
+		log.Error("Failed to call ui_onSignerStartup", "err", err)
+	}
+}
+
+func (ui *externalUI) OnInputRequired(info UserInputRequest) (UserInputResponse, error) {
+	var result UserInputResponse
+	if err := ui.client.Call(&result, "ui_onInputRequired", info); err != nil {
+		log.Error("Failed to call ui_onInputRequired", "err", err)
+		return UserInputResponse{}, err
+	}
+	return result, nil
+}
+
+// RegisterUIServer lets the signer push arbitrary notifications (e.g.
+// ui_showInfo) to the remote UI outside of the regular request/response
+// flow above, by exposing api over the same connection.
+func (ui *externalUI) RegisterUIServer(api *UIServerAPI) {
+	ui.client.RegisterName("clef", api)
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package fourbyte
+
+// This file seeds the handful of selectors common enough to be worth
+// shipping unconditionally; the full 4byte.directory export is pulled in at
+// build time by the generator below and merged into embeddedSelectors in
+// init().
+//
+//go:generate go run ./gen/gen4byte.go
+
+func init() {
+	embeddedSelectors = map[string][]string{
+		"a9059cbb": {"transfer(address,uint256)"},
+		"095ea7b3": {"approve(address,uint256)"},
+		"23b872dd": {"transferFrom(address,address,uint256)"},
+		"70a08231": {"balanceOf(address)"},
+		"18160ddd": {"totalSupply()"},
+		"dd62ed3e": {"allowance(address,address)"},
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// Package fourbyte resolves 4-byte ABI method selectors to human-readable
+// signatures, so the signer can show "transfer(0xabc..., 1000000)" instead of
+// raw calldata in ApproveTx.
+package fourbyte
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/truechain/truechain-engineering-code/crypto"
+	"github.com/truechain/truechain-engineering-code/log"
+)
+
+// embeddedSelectors holds the selector->signature pairs shipped with the
+// binary, generated from the well-known 4byte.directory export. It is
+// populated by 4byte_db.go, which is produced by `go generate`.
+var embeddedSelectors map[string][]string
+
+// Database is a lookup from 4-byte selector to the candidate method
+// signatures it could correspond to. Selectors collide relatively often
+// (crypto.Keccak256 truncated to 4 bytes), so a selector may map to more than
+// one signature.
+type Database struct {
+	mu        sync.RWMutex
+	selectors map[string][]string
+}
+
+var (
+	defaultOnce sync.Once
+	defaultDB   *Database
+)
+
+// Default returns the process-wide selector database, loading the embedded
+// table (and the user override file, if present) on first use.
+func Default() *Database {
+	defaultOnce.Do(func() {
+		db, err := newWithUserOverride()
+		if err != nil {
+			log.Warn("Failed to load 4byte override database", "err", err)
+			db = New()
+		}
+		defaultDB = db
+	})
+	return defaultDB
+}
+
+// New creates a Database seeded only with the embedded selector table.
+func New() *Database {
+	db := &Database{selectors: make(map[string][]string, len(embeddedSelectors))}
+	for sel, sigs := range embeddedSelectors {
+		db.selectors[sel] = append([]string{}, sigs...)
+	}
+	return db
+}
+
+// newWithUserOverride loads New() and then merges in ~/.truechain/4byte.json
+// if it exists, letting an operator teach the signer signatures that aren't
+// in the embedded table without a rebuild.
+func newWithUserOverride() (*Database, error) {
+	db := New()
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return db, nil
+	}
+	path := filepath.Join(home, ".truechain", "4byte.json")
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return db, nil
+		}
+		return db, err
+	}
+	var overrides map[string]string
+	if err := json.Unmarshal(raw, &overrides); err != nil {
+		return db, err
+	}
+	for sel, sig := range overrides {
+		db.selectors[sel] = appendUnique(db.selectors[sel], sig)
+	}
+	return db, nil
+}
+
+// Selectors returns every known signature for the 4-byte selector id, and
+// whether any were found at all.
+func (db *Database) Selectors(id [4]byte) ([]string, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	sigs, ok := db.selectors[fmt.Sprintf("%x", id)]
+	return sigs, ok
+}
+
+// AddSelector teaches the database a new method signature, e.g.
+// "transfer(address,uint256)", keying it by the first four bytes of its
+// Keccak256 hash. It returns the number of signatures now known for that
+// selector, so callers can tell whether this created a new collision.
+func (db *Database) AddSelector(signature string) (int, error) {
+	id := crypto.Keccak256([]byte(signature))[:4]
+	key := fmt.Sprintf("%x", id)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.selectors[key] = appendUnique(db.selectors[key], signature)
+	return len(db.selectors[key]), nil
+}
+
+func appendUnique(sigs []string, sig string) []string {
+	for _, s := range sigs {
+		if s == sig {
+			return sigs
+		}
+	}
+	return append(sigs, sig)
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package fourbyte
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/truechain/truechain-engineering-code/crypto"
+)
+
+func TestAddSelectorAndLookup(t *testing.T) {
+	db := New()
+	n, err := db.AddSelector("transfer(address,uint256)")
+	if err != nil {
+		t.Fatalf("AddSelector: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("got %d known signatures, want 1", n)
+	}
+	id := crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	var key [4]byte
+	copy(key[:], id)
+	sigs, ok := db.Selectors(key)
+	if !ok || len(sigs) != 1 || sigs[0] != "transfer(address,uint256)" {
+		t.Errorf("Selectors(%x) = %v, %v", key, sigs, ok)
+	}
+}
+
+func TestAddSelectorDeduplicates(t *testing.T) {
+	db := New()
+	db.AddSelector("transfer(address,uint256)")
+	n, err := db.AddSelector("transfer(address,uint256)")
+	if err != nil {
+		t.Fatalf("AddSelector: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("got %d known signatures after re-adding the same one, want 1", n)
+	}
+}
+
+func TestValidateCallDataUnknownSelector(t *testing.T) {
+	db := New()
+	msgs := db.ValidateCallData([]byte{0xde, 0xad, 0xbe, 0xef})
+	if len(msgs) != 1 || msgs[0].Typ != "Warning" || !strings.Contains(msgs[0].Message, "unknown method selector") {
+		t.Errorf("got %+v, want a single unknown-selector warning", msgs)
+	}
+}
+
+func TestValidateCallDataShortCalldata(t *testing.T) {
+	db := New()
+	msgs := db.ValidateCallData([]byte{0x01, 0x02})
+	if len(msgs) != 1 || msgs[0].Typ != "Warning" || !strings.Contains(msgs[0].Message, "not valid ABI") {
+		t.Errorf("got %+v, want a single malformed-calldata warning", msgs)
+	}
+}
+
+func TestValidateCallDataDecodesKnownSelector(t *testing.T) {
+	db := New()
+	sig := "foo(uint256)"
+	db.AddSelector(sig)
+
+	id := crypto.Keccak256([]byte(sig))[:4]
+	arg := make([]byte, 32)
+	arg[31] = 42
+	calldata := append(append([]byte{}, id...), arg...)
+
+	msgs := db.ValidateCallData(calldata)
+	if len(msgs) != 1 || msgs[0].Typ != "Info" {
+		t.Fatalf("got %+v, want a single decoded Info message", msgs)
+	}
+	want := "foo(42)"
+	if msgs[0].Message != want {
+		t.Errorf("got message %q, want %q", msgs[0].Message, want)
+	}
+}
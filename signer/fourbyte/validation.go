@@ -0,0 +1,149 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package fourbyte
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/truechain/truechain-engineering-code/accounts/abi"
+)
+
+// Message is a single piece of calldata validation feedback, shaped to drop
+// straight into a core.ValidationInfo{Typ: m.Typ, Message: m.Message} -
+// kept independent of the signer/core package so fourbyte has no import
+// cycle back through it.
+type Message struct {
+	Typ     string
+	Message string
+}
+
+// ValidateCallData inspects calldata and returns the Message entries
+// ApproveTx should add to request.Callinfo: a decoded human-readable call on
+// success, or a Warning explaining why it could not be decoded.
+func (db *Database) ValidateCallData(calldata []byte) []Message {
+	if len(calldata) == 0 {
+		return nil
+	}
+	if len(calldata) < 4 {
+		return []Message{{
+			Typ:     "Warning",
+			Message: fmt.Sprintf("Transaction data is not valid ABI (missing 4 byte selector, have %d bytes)", len(calldata)),
+		}}
+	}
+	var id [4]byte
+	copy(id[:], calldata[:4])
+
+	sigs, ok := db.Selectors(id)
+	if !ok {
+		return []Message{{
+			Typ:     "Warning",
+			Message: fmt.Sprintf("Transaction calls unknown method selector 0x%x", id),
+		}}
+	}
+
+	var infos []Message
+	if len(sigs) > 1 {
+		infos = append(infos, Message{
+			Typ:     "Warning",
+			Message: fmt.Sprintf("Method selector 0x%x has %d known colliding signatures: %s", id, len(sigs), strings.Join(sigs, ", ")),
+		})
+	}
+	message, err := decodeCall(sigs[0], calldata[4:])
+	if err != nil {
+		infos = append(infos, Message{
+			Typ:     "Warning",
+			Message: fmt.Sprintf("Failed to ABI-decode call to %s: %v", sigs[0], err),
+		})
+		return infos
+	}
+	infos = append(infos, Message{Typ: "Info", Message: message})
+	return infos
+}
+
+// decodeCall ABI-decodes argdata against signature (e.g.
+// "transfer(address,uint256)") and renders it as
+// "transfer(0xabc..., 1000000)". There is no bare type-list parser in the
+// accounts/abi package, so - like upstream clef - this hand-assembles a
+// throwaway single-method JSON ABI fragment and goes through the normal
+// abi.JSON / Method.Inputs.UnpackValues path.
+func decodeCall(signature string, argdata []byte) (string, error) {
+	name, argTypes, err := splitSignature(signature)
+	if err != nil {
+		return "", err
+	}
+	method, err := parseMethod(name, argTypes)
+	if err != nil {
+		return "", err
+	}
+	values, err := method.Inputs.UnpackValues(argdata)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%v", v)
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(parts, ", ")), nil
+}
+
+// parseMethod builds an abi.Method for name/argTypes by round-tripping a
+// single-entry JSON ABI fragment through abi.JSON, since accounts/abi has no
+// way to parse a bare list of type strings directly.
+func parseMethod(name string, argTypes []string) (abi.Method, error) {
+	type abiArg struct {
+		Type string `json:"type"`
+	}
+	type abiMethod struct {
+		Name   string   `json:"name"`
+		Type   string   `json:"type"`
+		Inputs []abiArg `json:"inputs"`
+	}
+	inputs := make([]abiArg, len(argTypes))
+	for i, t := range argTypes {
+		inputs[i] = abiArg{Type: strings.TrimSpace(t)}
+	}
+	fragment, err := json.Marshal([]abiMethod{{Name: name, Type: "function", Inputs: inputs}})
+	if err != nil {
+		return abi.Method{}, err
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(fragment)))
+	if err != nil {
+		return abi.Method{}, err
+	}
+	method, ok := parsed.Methods[name]
+	if !ok {
+		return abi.Method{}, fmt.Errorf("method %q not found after ABI round-trip", name)
+	}
+	return method, nil
+}
+
+// splitSignature splits "transfer(address,uint256)" into its method name and
+// argument type list.
+func splitSignature(signature string) (name string, argTypes []string, err error) {
+	open := strings.IndexByte(signature, '(')
+	if open < 0 || !strings.HasSuffix(signature, ")") {
+		return "", nil, fmt.Errorf("malformed signature %q", signature)
+	}
+	name = signature[:open]
+	inner := signature[open+1 : len(signature)-1]
+	if inner == "" {
+		return name, nil, nil
+	}
+	return name, strings.Split(inner, ","), nil
+}
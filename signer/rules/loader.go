@@ -0,0 +1,55 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package rules
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/truechain/truechain-engineering-code/log"
+	"github.com/truechain/truechain-engineering-code/signer/core"
+	"github.com/truechain/truechain-engineering-code/signer/storage"
+)
+
+// LoadPinnedRuleset reads the JavaScript ruleset at path, checks it hashes to
+// pinnedHash and, if so, returns a RuleSet wrapping next. Any mismatch (or
+// read/parse failure) is logged and next is returned unmodified, so the
+// signer falls through to the interactive UI rather than running an
+// unexpected script.
+func LoadPinnedRuleset(path string, pinnedHash [32]byte, next core.UIClientAPI, credentialsStorage storage.Storage) core.UIClientAPI {
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Warn("Failed to read ruleset file, disabling rules", "path", path, "err", err)
+		return next
+	}
+	if got := sha256.Sum256(source); got != pinnedHash {
+		log.Warn("Ruleset hash mismatch, disabling rules", "path", path,
+			"want", fmt.Sprintf("%x", pinnedHash), "got", fmt.Sprintf("%x", got))
+		return next
+	}
+	ruleSet, err := NewRuleEvaluator(next, credentialsStorage)
+	if err != nil {
+		log.Warn("Failed to initialize rule engine, disabling rules", "err", err)
+		return next
+	}
+	if err := ruleSet.Init(string(source)); err != nil {
+		log.Warn("Failed to load ruleset, disabling rules", "path", path, "err", err)
+		return next
+	}
+	return ruleSet
+}
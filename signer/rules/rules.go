@@ -0,0 +1,208 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// Package rules implements a JavaScript-driven auto-approval layer for the
+// signer. An operator-supplied script may answer Approve/Reject/Continue for
+// any incoming request; Continue falls through to the wrapped UI so the
+// rules only need to cover the cases the operator wants automated.
+package rules
+
+import (
+	"fmt"
+
+	"github.com/robertkrimen/otto"
+	"github.com/truechain/truechain-engineering-code/internal/trueapi"
+	"github.com/truechain/truechain-engineering-code/log"
+	"github.com/truechain/truechain-engineering-code/signer/core"
+	"github.com/truechain/truechain-engineering-code/signer/storage"
+)
+
+const (
+	// approved/rejected/continued are the three verdicts a rule function may
+	// return; anything else is treated as "continue".
+	approved  = "Approve"
+	rejected  = "Reject"
+	continued = "Continue"
+)
+
+// RuleSet wraps a UIClientAPI, consulting an operator-supplied JavaScript
+// ruleset before forwarding any request it doesn't fully answer.
+type RuleSet struct {
+	next               core.UIClientAPI // the UI consulted when the ruleset returns "Continue" or errors
+	vm                 *otto.Otto
+	credentialsStorage storage.Storage
+}
+
+// NewRuleEvaluator creates a RuleSet that falls back to next and persists
+// rule-script state (e.g. daily-spend counters) via credentialsStorage.
+func NewRuleEvaluator(next core.UIClientAPI, credentialsStorage storage.Storage) (*RuleSet, error) {
+	vm := otto.New()
+	r := &RuleSet{
+		next:               next,
+		vm:                 vm,
+		credentialsStorage: credentialsStorage,
+	}
+	if err := r.initStorageBridge(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Init loads and evaluates a ruleset's source, making its top-level
+// functions (ApproveTx, ApproveListing, ...) available to execute.
+func (r *RuleSet) Init(javascriptRules string) error {
+	_, err := r.vm.Run(javascriptRules)
+	return err
+}
+
+// initStorageBridge exposes storage.get/storage.put to the JS sandbox so a
+// ruleset can keep state, e.g. a running total for a daily-spend limit.
+func (r *RuleSet) initStorageBridge() error {
+	storageObj, err := r.vm.Object(`storage = {}`)
+	if err != nil {
+		return err
+	}
+	if err := storageObj.Set("put", func(call otto.FunctionCall) otto.Value {
+		key, _ := call.Argument(0).ToString()
+		value, _ := call.Argument(1).ToString()
+		r.credentialsStorage.Put(key, value)
+		return otto.Value{}
+	}); err != nil {
+		return err
+	}
+	return storageObj.Set("get", func(call otto.FunctionCall) otto.Value {
+		key, _ := call.Argument(0).ToString()
+		value, err := r.credentialsStorage.Get(key)
+		if err != nil {
+			value = ""
+		}
+		v, _ := r.vm.ToValue(value)
+		return v
+	})
+}
+
+// execute calls a JS function by name with args marshalled to JS values,
+// returning its otto.Value result. If the function is not defined by the
+// loaded ruleset, execute reports an error so the caller can fall through to
+// the wrapped UI.
+func (r *RuleSet) execute(jsfunc string, args ...interface{}) (otto.Value, error) {
+	fn, err := r.vm.Get(jsfunc)
+	if err != nil {
+		return otto.Value{}, err
+	}
+	if !fn.IsFunction() {
+		return otto.Value{}, fmt.Errorf("ruleset does not define %s", jsfunc)
+	}
+	ottoArgs := make([]interface{}, len(args))
+	for i, a := range args {
+		ottoArgs[i] = a
+	}
+	return fn.Call(fn, ottoArgs...)
+}
+
+// checkApproval runs jsfunc and interprets its string return value. A
+// "Continue" result, any other unrecognized value, or a call error all mean
+// "defer to the wrapped UI".
+func (r *RuleSet) checkApproval(jsfunc string, args ...interface{}) (string, error) {
+	v, err := r.execute(jsfunc, args...)
+	if err != nil {
+		return continued, err
+	}
+	verdict, err := v.ToString()
+	if err != nil {
+		return continued, err
+	}
+	switch verdict {
+	case approved, rejected:
+		return verdict, nil
+	default:
+		return continued, nil
+	}
+}
+
+// ApproveTx implements core.UIClientAPI.
+func (r *RuleSet) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, error) {
+	verdict, err := r.checkApproval("ApproveTx", request)
+	if err != nil {
+		log.Info("Rule execution failed, continuing to UI", "err", err)
+	}
+	switch verdict {
+	case approved:
+		return core.SignTxResponse{Transaction: request.Transaction, Approved: true}, nil
+	case rejected:
+		return core.SignTxResponse{Transaction: request.Transaction, Approved: false}, nil
+	default:
+		return r.next.ApproveTx(request)
+	}
+}
+
+// ApproveListing implements core.UIClientAPI.
+func (r *RuleSet) ApproveListing(request *core.ListRequest) (core.ListResponse, error) {
+	verdict, err := r.checkApproval("ApproveListing", request)
+	if err != nil {
+		log.Info("Rule execution failed, continuing to UI", "err", err)
+	}
+	switch verdict {
+	case approved:
+		return core.ListResponse{Accounts: request.Accounts}, nil
+	case rejected:
+		return core.ListResponse{Accounts: nil}, nil
+	default:
+		return r.next.ApproveListing(request)
+	}
+}
+
+// ApproveNewAccount implements core.UIClientAPI. Account creation is
+// security sensitive enough that it is never auto-approved by a rule; it
+// always defers to the wrapped UI.
+func (r *RuleSet) ApproveNewAccount(request *core.NewAccountRequest) (core.NewAccountResponse, error) {
+	return r.next.ApproveNewAccount(request)
+}
+
+// ShowError implements core.UIClientAPI by forwarding to the wrapped UI.
+func (r *RuleSet) ShowError(message string) {
+	r.next.ShowError(message)
+}
+
+// ShowInfo implements core.UIClientAPI by forwarding to the wrapped UI.
+func (r *RuleSet) ShowInfo(message string) {
+	r.next.ShowInfo(message)
+}
+
+// OnApprovedTx lets a ruleset observe every signed transaction, e.g. to
+// update a running daily-spend total, before forwarding to the wrapped UI.
+func (r *RuleSet) OnApprovedTx(tx trueapi.SignTransactionResult) {
+	if _, err := r.execute("OnApprovedTx", tx); err != nil {
+		log.Debug("OnApprovedTx not handled by ruleset", "err", err)
+	}
+	r.next.OnApprovedTx(tx)
+}
+
+// OnSignerStartup implements core.UIClientAPI by forwarding to the wrapped UI.
+func (r *RuleSet) OnSignerStartup(info core.StartupInfo) {
+	r.next.OnSignerStartup(info)
+}
+
+// OnInputRequired is never answered by a ruleset; it always defers to the
+// wrapped UI since it implies information only a human can supply.
+func (r *RuleSet) OnInputRequired(info core.UserInputRequest) (core.UserInputResponse, error) {
+	return r.next.OnInputRequired(info)
+}
+
+// RegisterUIServer implements core.UIClientAPI by forwarding to the wrapped UI.
+func (r *RuleSet) RegisterUIServer(api *core.UIServerAPI) {
+	r.next.RegisterUIServer(api)
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package rules
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/truechain/truechain-engineering-code/internal/trueapi"
+	"github.com/truechain/truechain-engineering-code/signer/core"
+	"github.com/truechain/truechain-engineering-code/signer/storage"
+)
+
+// fakeUI is a core.UIClientAPI that records whether any of its methods were
+// called, so tests can tell an Approve/Reject verdict (handled entirely by
+// the ruleset, never reaching the wrapped UI) apart from a Continue verdict
+// (falling through to it).
+type fakeUI struct {
+	approveTxCalled      bool
+	approveListingCalled bool
+}
+
+func (f *fakeUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, error) {
+	f.approveTxCalled = true
+	return core.SignTxResponse{Transaction: request.Transaction, Approved: true}, nil
+}
+
+func (f *fakeUI) ApproveListing(request *core.ListRequest) (core.ListResponse, error) {
+	f.approveListingCalled = true
+	return core.ListResponse{Accounts: request.Accounts}, nil
+}
+
+func (f *fakeUI) ApproveNewAccount(request *core.NewAccountRequest) (core.NewAccountResponse, error) {
+	return core.NewAccountResponse{Approved: true}, nil
+}
+
+func (f *fakeUI) ShowError(message string)                      {}
+func (f *fakeUI) ShowInfo(message string)                       {}
+func (f *fakeUI) OnApprovedTx(tx trueapi.SignTransactionResult) {}
+func (f *fakeUI) OnSignerStartup(info core.StartupInfo)         {}
+func (f *fakeUI) OnInputRequired(info core.UserInputRequest) (core.UserInputResponse, error) {
+	return core.UserInputResponse{}, nil
+}
+func (f *fakeUI) RegisterUIServer(api *core.UIServerAPI) {}
+
+func newTestRuleSet(t *testing.T, next core.UIClientAPI, javascriptRules string) *RuleSet {
+	t.Helper()
+	r, err := NewRuleEvaluator(next, &storage.NoStorage{})
+	if err != nil {
+		t.Fatalf("NewRuleEvaluator: %v", err)
+	}
+	if err := r.Init(javascriptRules); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return r
+}
+
+func TestApproveTxApprove(t *testing.T) {
+	next := &fakeUI{}
+	r := newTestRuleSet(t, next, `function ApproveTx(r) { return "Approve" }`)
+
+	resp, err := r.ApproveTx(&core.SignTxRequest{})
+	if err != nil {
+		t.Fatalf("ApproveTx: %v", err)
+	}
+	if !resp.Approved {
+		t.Error("got Approved = false, want true")
+	}
+	if next.approveTxCalled {
+		t.Error("wrapped UI was consulted despite the ruleset returning Approve")
+	}
+}
+
+func TestApproveTxReject(t *testing.T) {
+	next := &fakeUI{}
+	r := newTestRuleSet(t, next, `function ApproveTx(r) { return "Reject" }`)
+
+	resp, err := r.ApproveTx(&core.SignTxRequest{})
+	if err != nil {
+		t.Fatalf("ApproveTx: %v", err)
+	}
+	if resp.Approved {
+		t.Error("got Approved = true, want false")
+	}
+	if next.approveTxCalled {
+		t.Error("wrapped UI was consulted despite the ruleset returning Reject")
+	}
+}
+
+func TestApproveTxContinueFallsThroughToUI(t *testing.T) {
+	next := &fakeUI{}
+	r := newTestRuleSet(t, next, `function ApproveTx(r) { return "Continue" }`)
+
+	if _, err := r.ApproveTx(&core.SignTxRequest{}); err != nil {
+		t.Fatalf("ApproveTx: %v", err)
+	}
+	if !next.approveTxCalled {
+		t.Error("wrapped UI was not consulted despite the ruleset returning Continue")
+	}
+}
+
+func TestApproveTxUndefinedFunctionFallsThroughToUI(t *testing.T) {
+	next := &fakeUI{}
+	r := newTestRuleSet(t, next, `function ApproveListing(r) { return "Approve" }`)
+
+	if _, err := r.ApproveTx(&core.SignTxRequest{}); err != nil {
+		t.Fatalf("ApproveTx: %v", err)
+	}
+	if !next.approveTxCalled {
+		t.Error("wrapped UI was not consulted despite the ruleset not defining ApproveTx")
+	}
+}
+
+func TestApproveListingDispatch(t *testing.T) {
+	next := &fakeUI{}
+	r := newTestRuleSet(t, next, `function ApproveListing(r) { return "Reject" }`)
+
+	resp, err := r.ApproveListing(&core.ListRequest{})
+	if err != nil {
+		t.Fatalf("ApproveListing: %v", err)
+	}
+	if resp.Accounts != nil {
+		t.Errorf("got Accounts = %v, want nil", resp.Accounts)
+	}
+	if next.approveListingCalled {
+		t.Error("wrapped UI was consulted despite the ruleset returning Reject")
+	}
+}
+
+func TestLoadPinnedRulesetHashMismatchFallsBackToNext(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ruleset.js")
+	writeFile(t, path, `function ApproveTx(r) { return "Approve" }`)
+
+	var wrongHash [32]byte
+	next := &fakeUI{}
+	got := LoadPinnedRuleset(path, wrongHash, next, &storage.NoStorage{})
+
+	if got != next {
+		t.Error("got a ruleset-wrapped UI despite a hash mismatch, want the unmodified next UI")
+	}
+}
+
+func TestLoadPinnedRulesetHashMatchWrapsNext(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ruleset.js")
+	source := `function ApproveTx(r) { return "Approve" }`
+	writeFile(t, path, source)
+
+	next := &fakeUI{}
+	got := LoadPinnedRuleset(path, sha256.Sum256([]byte(source)), next, &storage.NoStorage{})
+
+	if _, ok := got.(*RuleSet); !ok {
+		t.Errorf("got %T, want a *RuleSet wrapping next", got)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing test ruleset: %v", err)
+	}
+}
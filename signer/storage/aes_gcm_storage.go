@@ -0,0 +1,165 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/truechain/truechain-engineering-code/log"
+)
+
+// AESEncryptedStorage is a Storage backed by a single JSON file on disk,
+// where every value is individually encrypted with AES-256-GCM under a key
+// derived from the operator's master password.
+type AESEncryptedStorage struct {
+	filename string
+	key      []byte
+	mu       sync.Mutex
+}
+
+// encryptedEntry is the on-disk representation of one stored value.
+type encryptedEntry struct {
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// NewAESEncryptedStorage creates a credential store persisted to filename,
+// with every entry encrypted under a key derived (via SHA-256) from
+// keyfilepass, the master password the signer read at startup.
+func NewAESEncryptedStorage(filename string, keyfilepass string) *AESEncryptedStorage {
+	sum := sha256.Sum256([]byte(keyfilepass))
+	return &AESEncryptedStorage{
+		filename: filename,
+		key:      sum[:],
+	}
+}
+
+// Put encrypts value and stores it under key, persisting the whole store to
+// disk. Errors are logged rather than returned, matching the rest of the
+// signer's storage.Storage contract.
+func (s *AESEncryptedStorage) Put(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.readEncryptedStorage()
+	if err != nil {
+		log.Warn("Failed to load credential store, starting fresh", "err", err)
+		data = make(map[string]encryptedEntry)
+	}
+	ciphertext, nonce, err := s.encrypt([]byte(value))
+	if err != nil {
+		log.Warn("Failed to encrypt credential", "err", err)
+		return
+	}
+	data[key] = encryptedEntry{Nonce: fmt.Sprintf("%x", nonce), Ciphertext: fmt.Sprintf("%x", ciphertext)}
+	if err := s.writeEncryptedStorage(data); err != nil {
+		log.Warn("Failed to persist credential store", "err", err)
+	}
+}
+
+// Get decrypts and returns the value stored under key.
+func (s *AESEncryptedStorage) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.readEncryptedStorage()
+	if err != nil {
+		return "", err
+	}
+	entry, ok := data[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	var nonce, ciphertext []byte
+	if _, err := fmt.Sscanf(entry.Nonce, "%x", &nonce); err != nil {
+		return "", err
+	}
+	if _, err := fmt.Sscanf(entry.Ciphertext, "%x", &ciphertext); err != nil {
+		return "", err
+	}
+	plaintext, err := s.decrypt(ciphertext, nonce)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (s *AESEncryptedStorage) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s *AESEncryptedStorage) encrypt(plaintext []byte) (ciphertext, nonce []byte, err error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return gcm.Seal(nil, nonce, plaintext, nil), nonce, nil
+}
+
+func (s *AESEncryptedStorage) decrypt(ciphertext, nonce []byte) ([]byte, error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func (s *AESEncryptedStorage) readEncryptedStorage() (map[string]encryptedEntry, error) {
+	raw, err := ioutil.ReadFile(s.filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]encryptedEntry), nil
+		}
+		return nil, err
+	}
+	data := make(map[string]encryptedEntry)
+	if len(raw) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *AESEncryptedStorage) writeEncryptedStorage(data map[string]encryptedEntry) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.filename), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.filename, raw, 0600)
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAESEncryptedStoragePutGet(t *testing.T) {
+	dir := t.TempDir()
+	s := NewAESEncryptedStorage(filepath.Join(dir, "vault.json"), "my-passphrase")
+
+	s.Put("foo", "bar")
+	got, err := s.Get("foo")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got != "bar" {
+		t.Errorf("got %q, want %q", got, "bar")
+	}
+}
+
+func TestAESEncryptedStorageGetMissing(t *testing.T) {
+	dir := t.TempDir()
+	s := NewAESEncryptedStorage(filepath.Join(dir, "vault.json"), "my-passphrase")
+
+	if _, err := s.Get("missing"); err != ErrNotFound {
+		t.Errorf("got err %v, want ErrNotFound", err)
+	}
+}
+
+func TestAESEncryptedStoragePersistsAcrossReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vault.json")
+
+	NewAESEncryptedStorage(path, "my-passphrase").Put("foo", "bar")
+
+	reloaded := NewAESEncryptedStorage(path, "my-passphrase")
+	got, err := reloaded.Get("foo")
+	if err != nil {
+		t.Fatalf("Get after reload: %v", err)
+	}
+	if got != "bar" {
+		t.Errorf("got %q, want %q", got, "bar")
+	}
+}
+
+func TestAESEncryptedStorageWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vault.json")
+
+	NewAESEncryptedStorage(path, "correct-passphrase").Put("foo", "bar")
+
+	wrong := NewAESEncryptedStorage(path, "wrong-passphrase")
+	if _, err := wrong.Get("foo"); err == nil {
+		t.Error("expected decryption to fail with the wrong passphrase, got nil error")
+	}
+}
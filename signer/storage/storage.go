@@ -0,0 +1,42 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// Package storage provides the small persistent key/value store the signer's
+// rule engine uses to remember state (e.g. daily-spend counters) across
+// restarts.
+package storage
+
+import "errors"
+
+// ErrNotFound is returned by Storage.Get for a key that has no value stored.
+var ErrNotFound = errors.New("key not found")
+
+// Storage is the interface the rules engine's JS sandbox is bound to via
+// storage.get/storage.put. Values are opaque strings; callers are
+// responsible for their own serialization.
+type Storage interface {
+	Put(key, value string)
+	Get(key string) (string, error)
+}
+
+// NoStorage is a Storage that persists nothing, used when the operator has
+// not configured a credential store.
+type NoStorage struct{}
+
+func (s *NoStorage) Put(key, value string) {}
+func (s *NoStorage) Get(key string) (string, error) {
+	return "", ErrNotFound
+}